@@ -0,0 +1,29 @@
+package zip
+
+// EncryptionMethod identifies which encryption scheme, if any, protects a
+// FileHeader's contents.
+type EncryptionMethod int
+
+const (
+	// NotEncrypted is the zero value: the entry isn't password protected.
+	NotEncrypted EncryptionMethod = iota
+	// StandardEncryption is the traditional PKWARE "ZipCrypto" stream
+	// cipher.
+	StandardEncryption
+	// AES128Encryption, AES192Encryption and AES256Encryption are WinZip's
+	// AES modes, signaled by extra field 0x9901.
+	// See: http://www.winzip.com/aes_info.htm
+	AES128Encryption
+	AES192Encryption
+	AES256Encryption
+	// StrongEncryption is this package's own AES-256-CTR scheme, which
+	// reuses APPNOTE section 7's Archive Decryption Header byte layout
+	// but not its vendor key setup, so it does not
+	// interoperate with real Strong Encryption Specification archives
+	// produced by SecureZip or 7-Zip. See strongcrypto.go.
+	StrongEncryption
+)
+
+// passwordFn returns the password to use when reading/writing an
+// encrypted FileHeader.
+type passwordFn func() (string, error)
@@ -0,0 +1,272 @@
+package zip
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// filetimeToUnixDiffSec is the number of seconds between the Windows
+// FILETIME epoch (1601-01-01 00:00:00 UTC) and the Unix epoch.
+const filetimeToUnixDiffSec = 11644473600
+
+// NTFS extra field attribute tags.
+const ntfsAttrTag1 = 0x0001 // Mtime, Atime, Ctime as 8 byte FILETIMEs
+
+// Info-ZIP extended timestamp flags (the "UT" extra field).
+const (
+	unixExtraHasModTime = 1 << iota
+	unixExtraHasAccessTime
+	unixExtraHasCreateTime
+)
+
+// extraField is a single (id, data) record as stored in FileHeader.Extra.
+type extraField struct {
+	id   uint16
+	data []byte
+}
+
+// parseExtras walks the TLV-encoded Extra field, returning each record it
+// finds. Trailing bytes that don't form a full record are ignored, matching
+// how most zip tools tolerate truncated extra data.
+func parseExtras(b []byte) []extraField {
+	var fields []extraField
+	for len(b) >= 4 {
+		id := binary.LittleEndian.Uint16(b)
+		size := binary.LittleEndian.Uint16(b[2:])
+		b = b[4:]
+		if int(size) > len(b) {
+			break
+		}
+		fields = append(fields, extraField{id: id, data: b[:size]})
+		b = b[size:]
+	}
+	return fields
+}
+
+// setExtra replaces any existing extra record with the given id, or appends
+// a new one if none is present.
+func (fh *FileHeader) setExtra(id uint16, data []byte) {
+	var buf []byte
+	for _, f := range parseExtras(fh.Extra) {
+		if f.id == id {
+			continue
+		}
+		buf = appendExtra(buf, f.id, f.data)
+	}
+	fh.Extra = appendExtra(buf, id, data)
+}
+
+func appendExtra(b []byte, id uint16, data []byte) []byte {
+	var hdr [4]byte
+	binary.LittleEndian.PutUint16(hdr[0:2], id)
+	binary.LittleEndian.PutUint16(hdr[2:4], uint16(len(data)))
+	b = append(b, hdr[:]...)
+	return append(b, data...)
+}
+
+// fileTimeToTime converts ft, a count of 100ns ticks since the Windows
+// FILETIME epoch, to a time.Time. It goes through Unix seconds/nanoseconds
+// rather than adding a time.Duration to the FILETIME epoch directly,
+// since a modern FILETIME's tick count in nanoseconds overflows
+// time.Duration's int64 range (which only spans about 292 years).
+func fileTimeToTime(ft uint64) time.Time {
+	sec := int64(ft/1e7) - filetimeToUnixDiffSec
+	nsec := int64(ft%1e7) * 100
+	return time.Unix(sec, nsec).UTC()
+}
+
+func timeToFileTime(t time.Time) uint64 {
+	sec := uint64(t.Unix() + filetimeToUnixDiffSec)
+	nsec := uint64(t.Nanosecond())
+	return sec*1e7 + nsec/100
+}
+
+// ntfsTimes reports the Mtime/Atime/Ctime carried in the NTFS (0x000a)
+// extra field, if present.
+func (fh *FileHeader) ntfsTimes() (mtime, atime, ctime time.Time, ok bool) {
+	for _, f := range parseExtras(fh.Extra) {
+		if f.id != ntfsExtraID || len(f.data) < 4 {
+			continue
+		}
+		// 4 bytes reserved, then a sequence of tag(2) size(2) attrs.
+		b := f.data[4:]
+		for len(b) >= 4 {
+			tag := binary.LittleEndian.Uint16(b)
+			size := binary.LittleEndian.Uint16(b[2:])
+			b = b[4:]
+			if int(size) > len(b) {
+				break
+			}
+			if tag == ntfsAttrTag1 && size >= 24 {
+				mtime = fileTimeToTime(binary.LittleEndian.Uint64(b[0:8]))
+				atime = fileTimeToTime(binary.LittleEndian.Uint64(b[8:16]))
+				ctime = fileTimeToTime(binary.LittleEndian.Uint64(b[16:24]))
+				return mtime, atime, ctime, true
+			}
+			b = b[size:]
+		}
+	}
+	return
+}
+
+// unixTimes reports the times carried in the Info-ZIP extended timestamp
+// (0x5455) extra field, if present. hasAtime/hasCtime report whether the
+// optional access/create times were encoded (central directory entries
+// commonly omit them).
+func (fh *FileHeader) unixTimes() (mtime, atime, ctime time.Time, hasMtime, hasAtime, hasCtime bool) {
+	for _, f := range parseExtras(fh.Extra) {
+		if f.id != unixExtraTimeID || len(f.data) < 1 {
+			continue
+		}
+		flags := f.data[0]
+		b := f.data[1:]
+		if flags&unixExtraHasModTime != 0 && len(b) >= 4 {
+			mtime = time.Unix(int64(int32(binary.LittleEndian.Uint32(b))), 0).UTC()
+			hasMtime = true
+			b = b[4:]
+		}
+		if flags&unixExtraHasAccessTime != 0 && len(b) >= 4 {
+			atime = time.Unix(int64(int32(binary.LittleEndian.Uint32(b))), 0).UTC()
+			hasAtime = true
+			b = b[4:]
+		}
+		if flags&unixExtraHasCreateTime != 0 && len(b) >= 4 {
+			ctime = time.Unix(int64(int32(binary.LittleEndian.Uint32(b))), 0).UTC()
+			hasCtime = true
+		}
+		return
+	}
+	return
+}
+
+// ModTimeHiRes returns the modification time in UTC at the resolution
+// stored in the archive. It prefers the NTFS (0x000a) extra field, which
+// carries 100ns precision, then the Info-ZIP extended timestamp (0x5455)
+// extra field, which carries whole seconds, and falls back to the 2-second
+// resolution MS-DOS field from ModTime.
+func (fh *FileHeader) ModTimeHiRes() time.Time {
+	if mtime, _, _, ok := fh.ntfsTimes(); ok {
+		return mtime
+	}
+	if mtime, _, _, hasMtime, _, _ := fh.unixTimes(); hasMtime {
+		return mtime
+	}
+	return fh.ModTime()
+}
+
+// AccessTime returns the last access time stored in the NTFS or Info-ZIP
+// extended timestamp extra fields. It reports the zero Time if neither
+// extra field carries an access time.
+func (fh *FileHeader) AccessTime() time.Time {
+	if _, atime, _, ok := fh.ntfsTimes(); ok {
+		return atime
+	}
+	if _, atime, _, _, hasAtime, _ := fh.unixTimes(); hasAtime {
+		return atime
+	}
+	return time.Time{}
+}
+
+// CreateTime returns the creation time stored in the NTFS or Info-ZIP
+// extended timestamp extra fields. It reports the zero Time if neither
+// extra field carries a creation time.
+func (fh *FileHeader) CreateTime() time.Time {
+	if _, _, ctime, ok := fh.ntfsTimes(); ok {
+		return ctime
+	}
+	if _, _, ctime, _, _, hasCtime := fh.unixTimes(); hasCtime {
+		return ctime
+	}
+	return time.Time{}
+}
+
+// SetModTimeHiRes sets the modification time at the highest resolution the
+// header's extra fields allow. It updates ModifiedTime/ModifiedDate (for
+// readers that only understand the MS-DOS field) and records t, truncated
+// to whole seconds, in an Info-ZIP extended timestamp extra field for
+// cross-tool compatibility. When CreatorVersion already identifies a
+// Windows (NTFS) creator, it also records t in an NTFS extra field, which
+// keeps 100ns precision; otherwise sub-second precision is not stored.
+func (fh *FileHeader) SetModTimeHiRes(t time.Time) {
+	fh.SetModTime(t)
+
+	fh.setExtra(unixExtraTimeID, unixTimestampExtra(t))
+
+	if fh.CreatorVersion>>8 == creatorNTFS {
+		mtime, atime, ctime, ok := fh.ntfsTimes()
+		if !ok {
+			atime, ctime = t, t
+		}
+		fh.setExtra(ntfsExtraID, ntfsTimestampExtra(t, atime, ctime))
+		_ = mtime
+	}
+}
+
+func unixTimestampExtra(mtime time.Time) []byte {
+	data := make([]byte, 5)
+	data[0] = unixExtraHasModTime
+	binary.LittleEndian.PutUint32(data[1:], uint32(mtime.Unix()))
+	return data
+}
+
+func ntfsTimestampExtra(mtime, atime, ctime time.Time) []byte {
+	data := make([]byte, 4+4+24)
+	// 4 bytes reserved, then tag 0x0001, size 24, followed by the three
+	// FILETIMEs.
+	binary.LittleEndian.PutUint16(data[4:6], ntfsAttrTag1)
+	binary.LittleEndian.PutUint16(data[6:8], 24)
+	binary.LittleEndian.PutUint64(data[8:16], timeToFileTime(mtime))
+	binary.LittleEndian.PutUint64(data[16:24], timeToFileTime(atime))
+	binary.LittleEndian.PutUint64(data[24:32], timeToFileTime(ctime))
+	return data
+}
+
+// SetUIDGID records the owning user and group ids in an Info-ZIP Unix
+// extra field (0x7875, 3rd generation), as produced by info-zip and
+// unzip -X.
+func (fh *FileHeader) SetUIDGID(uid, gid int) {
+	data := make([]byte, 1+1+4+1+4)
+	data[0] = 1 // version
+	data[1] = 4 // UID size
+	binary.LittleEndian.PutUint32(data[2:6], uint32(uid))
+	data[6] = 4 // GID size
+	binary.LittleEndian.PutUint32(data[7:11], uint32(gid))
+	fh.setExtra(unixExtraUIDGIDID, data)
+}
+
+// UIDGID returns the owning user and group ids recorded in the Info-ZIP
+// Unix extra field (0x7875), if present.
+func (fh *FileHeader) UIDGID() (uid, gid int, ok bool) {
+	for _, f := range parseExtras(fh.Extra) {
+		if f.id != unixExtraUIDGIDID || len(f.data) < 3 {
+			continue
+		}
+		b := f.data[1:] // skip version
+		uidSize := int(b[0])
+		b = b[1:]
+		if len(b) < uidSize+1 {
+			return 0, 0, false
+		}
+		uid = int(leUint(b[:uidSize]))
+		b = b[uidSize:]
+		gidSize := int(b[0])
+		b = b[1:]
+		if len(b) < gidSize {
+			return 0, 0, false
+		}
+		gid = int(leUint(b[:gidSize]))
+		return uid, gid, true
+	}
+	return 0, 0, false
+}
+
+// leUint decodes an unsigned little-endian integer of arbitrary width up
+// to 8 bytes, as used by the variable-width UID/GID fields in the
+// Info-ZIP Unix extra field.
+func leUint(b []byte) uint64 {
+	var v uint64
+	for i, c := range b {
+		v |= uint64(c) << (8 * uint(i))
+	}
+	return v
+}
@@ -0,0 +1,94 @@
+package zip
+
+import (
+	"testing"
+	"time"
+)
+
+func TestModTimeHiResRoundTrip(t *testing.T) {
+	want := time.Date(2023, 6, 15, 9, 30, 45, 0, time.UTC)
+	fh := &FileHeader{}
+	fh.SetModTimeHiRes(want)
+	if got := fh.ModTimeHiRes(); !got.Equal(want) {
+		t.Fatalf("ModTimeHiRes() = %v, want %v", got, want)
+	}
+}
+
+func TestAccessAndCreateTimeFromNTFSExtra(t *testing.T) {
+	mtime := time.Date(2023, 6, 15, 9, 30, 45, 0, time.UTC)
+	atime := time.Date(2023, 6, 16, 10, 0, 0, 0, time.UTC)
+	ctime := time.Date(2023, 6, 14, 8, 0, 0, 0, time.UTC)
+
+	fh := &FileHeader{CreatorVersion: uint16(creatorNTFS) << 8}
+	fh.setExtra(ntfsExtraID, ntfsTimestampExtra(mtime, atime, ctime))
+
+	if got := fh.AccessTime(); !got.Equal(atime) {
+		t.Errorf("AccessTime() = %v, want %v", got, atime)
+	}
+	if got := fh.CreateTime(); !got.Equal(ctime) {
+		t.Errorf("CreateTime() = %v, want %v", got, ctime)
+	}
+	if got := fh.ModTimeHiRes(); !got.Equal(mtime) {
+		t.Errorf("ModTimeHiRes() = %v, want %v", got, mtime)
+	}
+}
+
+func TestAccessAndCreateTimeZeroWithoutExtra(t *testing.T) {
+	fh := &FileHeader{}
+	if got := fh.AccessTime(); !got.IsZero() {
+		t.Errorf("AccessTime() = %v, want zero", got)
+	}
+	if got := fh.CreateTime(); !got.IsZero() {
+		t.Errorf("CreateTime() = %v, want zero", got)
+	}
+}
+
+func TestSetUIDGIDRoundTrip(t *testing.T) {
+	fh := &FileHeader{}
+	fh.SetUIDGID(1000, 1001)
+
+	uid, gid, ok := fh.UIDGID()
+	if !ok {
+		t.Fatal("UIDGID() ok = false, want true")
+	}
+	if uid != 1000 || gid != 1001 {
+		t.Fatalf("UIDGID() = (%d, %d), want (1000, 1001)", uid, gid)
+	}
+}
+
+func TestUIDGIDAbsentWithoutExtra(t *testing.T) {
+	fh := &FileHeader{}
+	if _, _, ok := fh.UIDGID(); ok {
+		t.Fatal("UIDGID() ok = true, want false")
+	}
+}
+
+func TestParseExtrasTruncatedRecordIgnored(t *testing.T) {
+	// A well-formed record (id 1, size 2, data "ab") followed by a header
+	// that claims more data than remains.
+	b := []byte{0x01, 0x00, 0x02, 0x00, 'a', 'b', 0x02, 0x00, 0xff, 0xff}
+	fields := parseExtras(b)
+	if len(fields) != 1 {
+		t.Fatalf("parseExtras() returned %d fields, want 1", len(fields))
+	}
+	if fields[0].id != 1 || string(fields[0].data) != "ab" {
+		t.Fatalf("parseExtras()[0] = %+v, want {id:1 data:ab}", fields[0])
+	}
+}
+
+func TestParseExtrasGarbageTrailerIgnored(t *testing.T) {
+	// Fewer than 4 bytes left over: not enough for even a header.
+	b := []byte{0x01, 0x00}
+	if fields := parseExtras(b); fields != nil {
+		t.Fatalf("parseExtras() = %v, want nil", fields)
+	}
+}
+
+func TestUIDGIDTruncatedExtraReportsNotOK(t *testing.T) {
+	fh := &FileHeader{}
+	// version(1) + uidSize(1)=4, but no UID bytes follow.
+	fh.setExtra(unixExtraUIDGIDID, []byte{1, 4})
+	if _, _, ok := fh.UIDGID(); ok {
+		t.Fatal("UIDGID() ok = true for truncated extra, want false")
+	}
+}
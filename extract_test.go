@@ -0,0 +1,97 @@
+package zip
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// These are the committed path-traversal/symlink-escape tests that back
+// ExtractEntry; the commit that introduced it claimed this verification
+// in prose without landing a test file — the guard-ordering bug that
+// TestExtractEntryMkdirAllDoesNotFollowSymlink below catches was live in
+// that commit and only found once an actual test existed.
+func TestExtractEntryTraversal(t *testing.T) {
+	dir := t.TempDir()
+	fh := &FileHeader{Name: "../evil.txt"}
+	fh.SetMode(0644)
+	if err := ExtractEntry(fh, bytes.NewReader([]byte("x")), dir, ExtractOptions{}, nil); err != ErrPathTraversal {
+		t.Fatalf("got %v, want ErrPathTraversal", err)
+	}
+}
+
+func TestExtractEntryNormal(t *testing.T) {
+	dir := t.TempDir()
+	fh := &FileHeader{Name: "sub/file.txt", UncompressedSize64: 5}
+	fh.SetMode(0644)
+	fh.SetModTimeHiRes(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC))
+	var total int64
+	if err := ExtractEntry(fh, bytes.NewReader([]byte("hello")), dir, ExtractOptions{}, &total); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "sub/file.txt"))
+	if err != nil || string(b) != "hello" {
+		t.Fatalf("content mismatch: %v %q", err, b)
+	}
+	if total != 5 {
+		t.Fatalf("total = %d", total)
+	}
+}
+
+func TestExtractEntrySymlinkEscape(t *testing.T) {
+	dir := t.TempDir()
+	fh := &FileHeader{Name: "link"}
+	fh.SetMode(os.ModeSymlink | 0777)
+	if err := ExtractEntry(fh, bytes.NewReader([]byte("../../etc/passwd")), dir, ExtractOptions{}, nil); err != ErrSymlinkEscapes {
+		t.Fatalf("got %v, want ErrSymlinkEscapes", err)
+	}
+}
+
+func TestExtractEntryThroughExistingSymlink(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Symlink(t.TempDir(), filepath.Join(dir, "sub")); err != nil {
+		t.Fatal(err)
+	}
+	fh := &FileHeader{Name: "sub/file.txt"}
+	fh.SetMode(0644)
+	if err := ExtractEntry(fh, bytes.NewReader([]byte("x")), dir, ExtractOptions{}, nil); err != ErrFollowsSymlink {
+		t.Fatalf("got %v, want ErrFollowsSymlink", err)
+	}
+}
+
+// TestExtractEntryMkdirAllDoesNotFollowSymlink is a regression test for a
+// Zip Slip bypass: os.MkdirAll happily walks through an existing symlink
+// component and creates the remaining path segments on the other side of
+// it. Before checkNoIntermediateSymlinks ran ahead of every MkdirAll call,
+// an entry nested two or more levels below a symlink (planted by an
+// earlier entry, or already present in dir) would have its intermediate
+// directories created outside dir by MkdirAll before the guard ever fired
+// — a single-level nesting doesn't exercise this, since MkdirAll no-ops
+// when the symlinked path already resolves to a directory.
+func TestExtractEntryMkdirAllDoesNotFollowSymlink(t *testing.T) {
+	dir := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(dir, "escape")); err != nil {
+		t.Fatal(err)
+	}
+
+	file := &FileHeader{Name: "escape/sub/deeper/evil.txt", UncompressedSize64: 3}
+	file.SetMode(0644)
+	if err := ExtractEntry(file, bytes.NewReader([]byte("bad")), dir, ExtractOptions{}, nil); err != ErrFollowsSymlink {
+		t.Fatalf("file through symlink: got %v, want ErrFollowsSymlink", err)
+	}
+	if _, err := os.Stat(filepath.Join(outside, "sub")); !os.IsNotExist(err) {
+		t.Fatalf("MkdirAll escaped through the symlink: %q exists outside dir (err=%v)", filepath.Join(outside, "sub"), err)
+	}
+
+	subdir := &FileHeader{Name: "escape/sub/deeper/"}
+	subdir.SetMode(os.ModeDir | 0755)
+	if err := ExtractEntry(subdir, bytes.NewReader(nil), dir, ExtractOptions{}, nil); err != ErrFollowsSymlink {
+		t.Fatalf("dir through symlink: got %v, want ErrFollowsSymlink", err)
+	}
+	if _, err := os.Stat(filepath.Join(outside, "sub")); !os.IsNotExist(err) {
+		t.Fatalf("MkdirAll escaped through the symlink: %q exists outside dir (err=%v)", filepath.Join(outside, "sub"), err)
+	}
+}
@@ -0,0 +1,179 @@
+package zip
+
+import (
+	"compress/flate"
+	"errors"
+	"io"
+	"sync"
+)
+
+// A Compressor returns a new compressing writer, writing to w.
+// The WriteCloser's Close method must be used to flush pending data to w.
+type Compressor func(w io.Writer) (io.WriteCloser, error)
+
+// A Decompressor returns a new decompressing reader, reading from r.
+// The ReadCloser's Close method must be used to release associated resources.
+type Decompressor func(r io.Reader) io.ReadCloser
+
+var flateWriterPool sync.Pool
+
+func newFlateWriter(w io.Writer) io.WriteCloser {
+	fw, ok := flateWriterPool.Get().(*flate.Writer)
+	if ok {
+		fw.Reset(w)
+	} else {
+		fw, _ = flate.NewWriter(w, flate.DefaultCompression)
+	}
+	return &pooledFlateWriter{fw: fw}
+}
+
+// pooledFlateWriter returns its underlying *flate.Writer to flateWriterPool
+// on Close, so repeated calls to the built-in Deflate Compressor don't pay
+// flate.NewWriter's table-building cost each time.
+type pooledFlateWriter struct {
+	mu sync.Mutex
+	fw *flate.Writer
+}
+
+func (w *pooledFlateWriter) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.fw == nil {
+		return 0, errors.New("zip: write to closed writer")
+	}
+	return w.fw.Write(p)
+}
+
+func (w *pooledFlateWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var err error
+	if w.fw != nil {
+		err = w.fw.Close()
+		flateWriterPool.Put(w.fw)
+		w.fw = nil
+	}
+	return err
+}
+
+var flateReaderPool sync.Pool
+
+func newFlateReader(r io.Reader) io.ReadCloser {
+	fr, ok := flateReaderPool.Get().(io.ReadCloser)
+	if ok {
+		fr.(flate.Resetter).Reset(r, nil)
+	} else {
+		fr = flate.NewReader(r)
+	}
+	return &pooledFlateReader{fr: fr}
+}
+
+type pooledFlateReader struct {
+	mu sync.Mutex
+	fr io.ReadCloser
+}
+
+func (r *pooledFlateReader) Read(p []byte) (n int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.fr == nil {
+		return 0, errors.New("zip: read from closed reader")
+	}
+	return r.fr.Read(p)
+}
+
+func (r *pooledFlateReader) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var err error
+	if r.fr != nil {
+		err = r.fr.Close()
+		flateReaderPool.Put(r.fr)
+		r.fr = nil
+	}
+	return err
+}
+
+var (
+	compressors   sync.Map // map[uint16]Compressor
+	decompressors sync.Map // map[uint16]Decompressor
+)
+
+func init() {
+	compressors.Store(Store, Compressor(func(w io.Writer) (io.WriteCloser, error) {
+		return nopWriteCloser{w}, nil
+	}))
+	compressors.Store(Deflate, Compressor(func(w io.Writer) (io.WriteCloser, error) {
+		return newFlateWriter(w), nil
+	}))
+
+	decompressors.Store(Store, Decompressor(io.NopCloser))
+	decompressors.Store(Deflate, Decompressor(newFlateReader))
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+// RegisterDecompressor allows custom decompressors for a specified method
+// ID. Store and Deflate are built in, BZIP2 has a built-in Decompressor,
+// and LZMA and Zstd have stand-ins that fail with ErrMethodNotImplemented
+// (see methods.go).
+//
+// This is analogous to archive/zip's package-level RegisterDecompressor
+// and lets a caller add a method that has no registered Decompressor
+// without forking this package. It panics if method already has one, so
+// it cannot replace the built-in Store, Deflate or BZIP2 decompressors or
+// the LZMA and Zstd stand-ins.
+func RegisterDecompressor(method uint16, d Decompressor) {
+	if _, dup := decompressors.LoadOrStore(method, d); dup {
+		panic("decompressor already registered")
+	}
+}
+
+// RegisterCompressor registers custom compressors for a specified method
+// ID. Store and Deflate are built in, and LZMA and Zstd have stand-ins that
+// fail with ErrMethodNotImplemented. It panics if method already has a
+// Compressor, so none of those can be replaced through it.
+func RegisterCompressor(method uint16, comp Compressor) {
+	if _, dup := compressors.LoadOrStore(method, comp); dup {
+		panic("compressor already registered")
+	}
+}
+
+// compressor returns the Compressor registered for method, or nil if none
+// is registered.
+func compressor(method uint16) Compressor {
+	ci, ok := compressors.Load(method)
+	if !ok {
+		return nil
+	}
+	return ci.(Compressor)
+}
+
+// decompressor returns the Decompressor registered for method, or nil if
+// none is registered.
+func decompressor(method uint16) Decompressor {
+	di, ok := decompressors.Load(method)
+	if !ok {
+		return nil
+	}
+	return di.(Decompressor)
+}
+
+// NOTE: the request this file answers asked for the package-level registry
+// "plus per-Reader/per-Writer override maps" (the same shape as stdlib
+// archive/zip's Reader.RegisterDecompressor/Writer.RegisterCompressor,
+// which let one Reader/Writer override a method without affecting every
+// other one using the package-level registry). Only the package-level
+// registry above exists. The override maps are not a missing follow-up
+// detail — they cannot be added without a *Reader/*Writer type to hang
+// them off of, and this checkout has neither (no reader.go/writer.go).
+// That gap is also why chunk0-2, chunk0-4 and chunk0-6 each landed
+// primitives (method stand-ins, a parallel-deflate block writer, a
+// single-entry extractor) that compressor/decompressor lookups, a
+// CreateParallel, or an ExtractTo could call, but that nothing in this
+// package currently does call: every one of those requests describes a
+// Reader/Writer-level API, and this is the prerequisite none of them have.
+// This should have been raised as a blocker before building further
+// tickets on top of it rather than discovered afterward.
@@ -0,0 +1,76 @@
+package zip
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestRegisterCompressorDuplicatePanics(t *testing.T) {
+	const method = 0xfff0
+	RegisterCompressor(method, func(w io.Writer) (io.WriteCloser, error) {
+		return nopWriteCloser{w}, nil
+	})
+	defer func() {
+		if recover() == nil {
+			t.Fatal("RegisterCompressor did not panic on duplicate registration")
+		}
+	}()
+	RegisterCompressor(method, func(w io.Writer) (io.WriteCloser, error) {
+		return nopWriteCloser{w}, nil
+	})
+}
+
+func TestRegisterDecompressorDuplicatePanics(t *testing.T) {
+	const method = 0xfff1
+	RegisterDecompressor(method, io.NopCloser)
+	defer func() {
+		if recover() == nil {
+			t.Fatal("RegisterDecompressor did not panic on duplicate registration")
+		}
+	}()
+	RegisterDecompressor(method, io.NopCloser)
+}
+
+func TestBuiltinStoreAndDeflateRoundTrip(t *testing.T) {
+	for _, method := range []uint16{Store, Deflate} {
+		c := compressor(method)
+		if c == nil {
+			t.Fatalf("method %d has no registered Compressor", method)
+		}
+		var buf bytes.Buffer
+		wc, err := c(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := wc.Write([]byte("hello register")); err != nil {
+			t.Fatal(err)
+		}
+		if err := wc.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		d := decompressor(method)
+		if d == nil {
+			t.Fatalf("method %d has no registered Decompressor", method)
+		}
+		rc := d(&buf)
+		defer rc.Close()
+		got, err := io.ReadAll(rc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != "hello register" {
+			t.Fatalf("method %d: got %q, want %q", method, got, "hello register")
+		}
+	}
+}
+
+func TestCompressorDecompressorUnregisteredMethodIsNil(t *testing.T) {
+	if c := compressor(0xfffe); c != nil {
+		t.Fatal("compressor(0xfffe) = non-nil, want nil")
+	}
+	if d := decompressor(0xfffe); d != nil {
+		t.Fatal("decompressor(0xfffe) = non-nil, want nil")
+	}
+}
@@ -0,0 +1,282 @@
+package zip
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+)
+
+// This file is NOT an implementation of APPNOTE.TXT section 7, the Strong
+// Encryption Specification (SES), despite reusing its extra field ID,
+// masked-local-header flag bit, algorithm IDs and Archive Decryption
+// Header wire layout below. Real SES implementations (SecureZip, 7-Zip)
+// derive their central-directory key with vendor-specific key setup that
+// isn't publicly documented in enough detail to reproduce here; what
+// follows instead reuses WinZip AES's PBKDF2-HMAC-SHA1 construction
+// (see pbkdf2Key) to fill the same byte layout. An archive built with
+// EncryptArchiveDecryptionHeader will only ever be read back by
+// DecryptArchiveDecryptionHeader in this package — it is a self-consistent,
+// non-interoperable scheme, not SES support, and won't decrypt (or be
+// decrypted by) a real SecureZip/7-Zip SES archive.
+//
+// A file encrypted under real SES carries a 0x0017 extra field; an archive
+// with an encrypted central directory additionally sets the
+// masked-local-header bit (1<<13) in the central directory's general
+// purpose flags and is followed by an Archive Decryption Header before the
+// first local file header. The constants below name those wire-format
+// values for the benefit of the byte layout reused here.
+const (
+	strongEncryptionExtraID = 0x0017 // Strong Encryption Header Extra Field
+
+	// centralDirMaskedFlag marks a central directory whose local header
+	// fields are masked and whose real values must be read from the
+	// central directory entry instead (APPNOTE 7.2).
+	centralDirMaskedFlag = 1 << 13
+)
+
+// SES algorithm IDs (APPNOTE 7.4.3).
+const (
+	sesAlgDES      = 0x6601
+	sesAlgRC2old   = 0x6602
+	sesAlg3DES168  = 0x6603
+	sesAlg3DES112  = 0x6609
+	sesAlgAES128   = 0x660e
+	sesAlgAES192   = 0x660f
+	sesAlgAES256   = 0x6610
+	sesAlgRC2      = 0x6702
+	sesAlgBlowfish = 0x6720
+	sesAlgTwofish  = 0x6721
+	sesAlgRC4      = 0x6801
+)
+
+// sesKDFIterations is the PBKDF2 iteration count used to derive the
+// central-directory encryption key and password verifier from a password.
+// This matches the iteration count WinZip's AES extension uses; it is not
+// specified anywhere by APPNOTE's Strong Encryption Specification, which
+// doesn't mandate PBKDF2 at all (see the file-level comment above).
+const sesKDFIterations = 1000
+
+// ErrIncorrectPassword is returned by DecryptArchiveDecryptionHeader when
+// the supplied password's derived verifier doesn't match the one stored
+// in the Archive Decryption Header.
+var ErrIncorrectPassword = errors.New("zip: incorrect password")
+
+// pbkdf2Key implements PBKDF2 (RFC 2898) with HMAC-SHA1 as the PRF, the
+// same construction WinZip's AES extra field uses for key derivation. This
+// is not what APPNOTE's Strong Encryption Specification (SES) uses; see
+// the file-level comment above.
+func pbkdf2Key(password, salt []byte, iter, keyLen int) []byte {
+	prf := hmac.New(sha1.New, password)
+	hashLen := prf.Size()
+	numBlocks := (keyLen + hashLen - 1) / hashLen
+
+	var buf [4]byte
+	dk := make([]byte, 0, numBlocks*hashLen)
+	u := make([]byte, hashLen)
+	for block := 1; block <= numBlocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(buf[:], uint32(block))
+		prf.Write(buf[:])
+		t := prf.Sum(nil)
+		copy(u, t)
+		for n := 2; n <= iter; n++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for x := range t {
+				t[x] ^= u[x]
+			}
+		}
+		dk = append(dk, t...)
+	}
+	return dk[:keyLen]
+}
+
+// deriveStrongEncryptionKey derives the AES-256-CTR key and a 2-byte
+// password verifier from password and salt (the header's IV). The
+// verifier lets DecryptArchiveDecryptionHeader reject a wrong password
+// without attempting to decrypt anything.
+func deriveStrongEncryptionKey(password string, salt []byte, keyLen int) (key, verifier []byte) {
+	dk := pbkdf2Key([]byte(password), salt, sesKDFIterations, keyLen+2)
+	return dk[:keyLen], dk[keyLen:]
+}
+
+func aesCTR(key, iv, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(data))
+	cipher.NewCTR(block, iv).XORKeyStream(out, data)
+	return out, nil
+}
+
+// archiveDecryptionHeader mirrors the byte layout of the APPNOTE 7.2
+// Archive Decryption Header, but with a key-verification value derived by
+// pbkdf2Key rather than real SES vendor key setup — see the file-level
+// comment above. It precedes the first local file header in an archive
+// with a masked (encrypted) central directory.
+type archiveDecryptionHeader struct {
+	iv      []byte
+	format  uint16
+	algID   uint16
+	bitLen  uint16
+	flags   uint16
+	erdData []byte // encrypted random data
+	vData   []byte // password verification value
+	vCRC32  uint32 // CRC-32 of vData
+}
+
+func marshalArchiveDecryptionHeader(h *archiveDecryptionHeader) []byte {
+	inner := make([]byte, 0, 10+len(h.erdData)+4+2+len(h.vData)+4)
+	inner = appendUint16(inner, h.format)
+	inner = appendUint16(inner, h.algID)
+	inner = appendUint16(inner, h.bitLen)
+	inner = appendUint16(inner, h.flags)
+	inner = appendUint16(inner, uint16(len(h.erdData)))
+	inner = append(inner, h.erdData...)
+	inner = appendUint32(inner, 0) // Reserved1: no certificate processing
+	inner = appendUint16(inner, uint16(len(h.vData)+4))
+	inner = append(inner, h.vData...)
+	inner = appendUint32(inner, h.vCRC32)
+
+	buf := make([]byte, 0, 2+len(h.iv)+4+len(inner))
+	buf = appendUint16(buf, uint16(len(h.iv)))
+	buf = append(buf, h.iv...)
+	buf = appendUint32(buf, uint32(len(inner)))
+	buf = append(buf, inner...)
+	return buf
+}
+
+func parseArchiveDecryptionHeader(b []byte) (h *archiveDecryptionHeader, rest []byte, err error) {
+	if len(b) < 2 {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	ivSize := binary.LittleEndian.Uint16(b)
+	b = b[2:]
+	if len(b) < int(ivSize)+4 {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	iv := append([]byte(nil), b[:ivSize]...)
+	b = b[ivSize:]
+
+	dataSize := binary.LittleEndian.Uint32(b)
+	b = b[4:]
+	if uint64(len(b)) < uint64(dataSize) {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	inner, rest := b[:dataSize], b[dataSize:]
+
+	if len(inner) < 10 {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	h = &archiveDecryptionHeader{iv: iv}
+	h.format = binary.LittleEndian.Uint16(inner[0:2])
+	h.algID = binary.LittleEndian.Uint16(inner[2:4])
+	h.bitLen = binary.LittleEndian.Uint16(inner[4:6])
+	h.flags = binary.LittleEndian.Uint16(inner[6:8])
+	erdSize := binary.LittleEndian.Uint16(inner[8:10])
+	inner = inner[10:]
+	if len(inner) < int(erdSize)+4 {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	h.erdData = append([]byte(nil), inner[:erdSize]...)
+	inner = inner[erdSize:] // Reserved1 follows; certificate processing (Reserved2) isn't supported.
+	inner = inner[4:]
+
+	if len(inner) < 2 {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	vSize := binary.LittleEndian.Uint16(inner[0:2])
+	inner = inner[2:]
+	if vSize < 4 || len(inner) < int(vSize) {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	h.vData = append([]byte(nil), inner[:vSize-4]...)
+	h.vCRC32 = binary.LittleEndian.Uint32(inner[vSize-4 : vSize])
+	return h, rest, nil
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v), byte(v>>8))
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+// EncryptArchiveDecryptionHeader builds an Archive Decryption Header (in
+// the APPNOTE 7.2 byte layout, algorithm ID 0x6610/AES-256) for a masked
+// central directory encrypted under password, using this package's own
+// PBKDF2-derived key setup rather than real SES vendor key setup — see the
+// file-level comment above; an archive built this way only round-trips
+// through DecryptArchiveDecryptionHeader in this package, not through
+// SecureZip or 7-Zip. It returns the marshaled header, to be written
+// immediately before the first local file header, and the derived key the
+// caller uses to encrypt the central directory with aesCTR(key, iv, ...).
+func EncryptArchiveDecryptionHeader(password string) (header []byte, key []byte, err error) {
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, nil, err
+	}
+	key, verifier := deriveStrongEncryptionKey(password, iv, 32)
+
+	erd := make([]byte, 12)
+	if _, err := rand.Read(erd); err != nil {
+		return nil, nil, err
+	}
+	encryptedErd, err := aesCTR(key, iv, erd)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	h := &archiveDecryptionHeader{
+		iv:      iv,
+		format:  3,
+		algID:   sesAlgAES256,
+		bitLen:  256,
+		erdData: encryptedErd,
+		vData:   verifier,
+		vCRC32:  crc32.ChecksumIEEE(verifier),
+	}
+	return marshalArchiveDecryptionHeader(h), key, nil
+}
+
+// DecryptArchiveDecryptionHeader parses an Archive Decryption Header from
+// the start of data, derives the central-directory key from password, and
+// checks it against the header's password verification value before
+// returning it. It returns the derived key and the remainder of data
+// following the header — the masked, AES-256-CTR-encrypted central
+// directory, to be decrypted with aesCTR(key, header's IV, ...).
+func DecryptArchiveDecryptionHeader(data []byte, password string) (key []byte, rest []byte, err error) {
+	h, rest, err := parseArchiveDecryptionHeader(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if h.algID != sesAlgAES256 {
+		return nil, nil, errors.New("zip: unsupported strong-encryption algorithm")
+	}
+	if crc32.ChecksumIEEE(h.vData) != h.vCRC32 {
+		return nil, nil, errors.New("zip: corrupt archive decryption header")
+	}
+
+	key, verifier := deriveStrongEncryptionKey(password, h.iv, 32)
+	if !hmac.Equal(verifier, h.vData) {
+		return nil, nil, ErrIncorrectPassword
+	}
+	return key, rest, nil
+}
+
+// NOTE: This checkout has no reader.go/writer.go, so there's no *Reader/
+// *Writer to thread EncryptArchiveDecryptionHeader/
+// DecryptArchiveDecryptionHeader, aesCTR and the masked-local-header flag
+// through automatically — a caller has to invoke them directly around the
+// central directory bytes for now. The existing WinZip AES path remains
+// the default; EncryptionMethod only gains a StrongEncryption value here.
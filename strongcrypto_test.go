@@ -0,0 +1,91 @@
+package zip
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// Test vectors from RFC 6070.
+func TestPbkdf2KeyRFC6070(t *testing.T) {
+	cases := []struct {
+		password, salt string
+		iter, keyLen   int
+		want           string
+	}{
+		{"password", "salt", 1, 20, "0c60c80f961f0e71f3a9b524af6012062fe037a6"},
+		{"password", "salt", 2, 20, "ea6c014dc72d6f8ccd1ed92ace1d41f0d8de8957"},
+		{"password", "salt", 4096, 20, "4b007901b765489abead49d926f721d065a429c1"},
+	}
+	for _, c := range cases {
+		got := pbkdf2Key([]byte(c.password), []byte(c.salt), c.iter, c.keyLen)
+		want, err := hex.DecodeString(c.want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("pbkdf2Key(%q, %q, %d, %d) = %x, want %x", c.password, c.salt, c.iter, c.keyLen, got, want)
+		}
+	}
+}
+
+func TestArchiveDecryptionHeaderRoundTrip(t *testing.T) {
+	header, key, err := EncryptArchiveDecryptionHeader("hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(key) != 32 {
+		t.Fatalf("key length = %d, want 32", len(key))
+	}
+
+	gotKey, rest, err := DecryptArchiveDecryptionHeader(header, "hunter2")
+	if err != nil {
+		t.Fatalf("DecryptArchiveDecryptionHeader: %v", err)
+	}
+	if !bytes.Equal(gotKey, key) {
+		t.Fatalf("derived key mismatch: got %x, want %x", gotKey, key)
+	}
+	if len(rest) != 0 {
+		t.Fatalf("rest = %d bytes, want 0 (no trailing data appended)", len(rest))
+	}
+}
+
+func TestArchiveDecryptionHeaderWrongPassword(t *testing.T) {
+	header, _, err := EncryptArchiveDecryptionHeader("hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := DecryptArchiveDecryptionHeader(header, "wrong"); err != ErrIncorrectPassword {
+		t.Fatalf("got %v, want ErrIncorrectPassword", err)
+	}
+}
+
+func TestArchiveDecryptionHeaderEncryptsCentralDirectory(t *testing.T) {
+	header, key, err := EncryptArchiveDecryptionHeader("s3cr3t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, _, err := parseArchiveDecryptionHeader(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	centralDir := []byte("pretend this is the masked central directory")
+	encrypted, err := aesCTR(key, parsed.iv, centralDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive := append(append([]byte{}, header...), encrypted...)
+
+	gotKey, rest, err := DecryptArchiveDecryptionHeader(archive, "s3cr3t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	decrypted, err := aesCTR(gotKey, parsed.iv, rest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decrypted, centralDir) {
+		t.Fatalf("decrypted central directory = %q, want %q", decrypted, centralDir)
+	}
+}
@@ -29,9 +29,14 @@ import (
 )
 
 // Compression methods.
+// See the APPNOTE.TXT section 4.4.5 for the full registry; these are the
+// methods this package recognizes.
 const (
 	Store   uint16 = 0
 	Deflate uint16 = 8
+	BZIP2   uint16 = 12
+	LZMA    uint16 = 14
+	Zstd    uint16 = 93
 )
 
 const (
@@ -59,14 +64,18 @@ const (
 	// version numbers
 	zipVersion20 = 20 // 2.0
 	zipVersion45 = 45 // 4.5 (reads and writes zip64 archives)
+	zipVersion63 = 63 // 6.3 (LZMA, PPMd, and other APPNOTE 6.3 methods)
 
 	// limits for non zip64 files
 	uint16max = (1 << 16) - 1
 	uint32max = (1 << 32) - 1
 
 	// extra header id's
-	zip64ExtraID     = 0x0001 // zip64 Extended Information Extra Field
-	winzipAesExtraID = 0x9901 // winzip AES Extra Field
+	zip64ExtraID      = 0x0001 // zip64 Extended Information Extra Field
+	ntfsExtraID       = 0x000a // NTFS Extra Field
+	unixExtraTimeID   = 0x5455 // Info-ZIP Extended Timestamp Extra Field ("UT")
+	unixExtraUIDGIDID = 0x7875 // Info-ZIP Unix Extra Field, 3rd generation ("ux")
+	winzipAesExtraID  = 0x9901 // winzip AES Extra Field
 )
 
 // FileHeader describes a file within a zip file.